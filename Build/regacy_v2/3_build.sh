@@ -0,0 +1,16 @@
+#!/bin/sh
+set -e
+cd "$(dirname "$0")"
+
+# go.modが存在しない場合にのみ実行
+if [ ! -f go.mod ]; then
+    go mod init myapp
+fi
+
+# 依存関係の整理
+go mod tidy
+
+echo "Building the project..."
+CGO_ENABLED=1 go build
+
+echo "Build process completed."
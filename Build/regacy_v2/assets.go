@@ -0,0 +1,48 @@
+package main
+
+import (
+	"embed"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+)
+
+// ディスク上の既定の画像パス
+const (
+	defaultBannerPath = "../Img/banner.png"
+	defaultIconPath   = "../Img/icon.png"
+)
+
+// exe 単体でも見た目が崩れないように既定の画像を埋め込む
+//
+//go:embed assets/*.png
+var embeddedAssets embed.FS
+
+// loadAsset は path のファイルを読み込む。読み込めない場合は assets/ に埋め込んだ name を使う
+func loadAsset(path, name string) fyne.Resource {
+	res, err := fyne.LoadResourceFromPath(path)
+	if err == nil {
+		return res
+	}
+	data, embedErr := embeddedAssets.ReadFile("assets/" + name)
+	if embedErr != nil {
+		log.Printf("Failed to load %q: %v", path, err)
+		return nil
+	}
+	log.Printf("%q not found, using embedded %s: %v", path, name, err)
+	return fyne.NewStaticResource(name, data)
+}
+
+// loadBanner は path の画像を読み込む。ファイルが無い場合は埋め込みのバナーを使う
+func loadBanner(path string) *canvas.Image {
+	return canvas.NewImageFromResource(bannerResource(path))
+}
+
+// bannerResource は path のバナー画像を返す。空の場合は既定のパスを使う
+func bannerResource(path string) fyne.Resource {
+	if path == "" {
+		path = defaultBannerPath
+	}
+	return loadAsset(path, "banner.png")
+}
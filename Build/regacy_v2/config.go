@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/ini.v1"
+)
+
+const (
+	configFile = "config.ini"
+	// Reset to Defaults の退避先。移行時と読み込み失敗時は別の名前に退避し、互いに上書きしない
+	configBackupFile = "config.bak.ini"
+)
+
+// 起動できるアプリケーション（config.ini のキー名と同じ）
+var appNames = []string{"Maya", "Blender", "AfterEffects", "Photoshop"}
+
+// 各アプリケーションの初期パス（Windows）
+var defaultAppPaths = map[string]string{
+	"Maya":         `C:\Program Files\Autodesk\Maya2022\bin\maya.exe`,
+	"Blender":      `C:\Program Files\Blender Foundation\Blender 2.93\blender.exe`,
+	"AfterEffects": `C:\Program Files\Adobe\Adobe After Effects 2023\Support Files\AfterFX.exe`,
+	"Photoshop":    `C:\Program Files\Adobe\Adobe Photoshop 2023\Photoshop.exe`,
+}
+
+// 各アプリケーションの初期パス（Linux）。Linux 版の無いアプリケーションは空にする
+var defaultLinuxAppPaths = map[string]string{
+	"Maya":         "/usr/autodesk/maya2022/bin/maya",
+	"Blender":      "/usr/bin/blender",
+	"AfterEffects": "",
+	"Photoshop":    "",
+}
+
+// defaultAppPath は実行中の OS での app の初期パスを返す
+func defaultAppPath(app string) string {
+	if runtime.GOOS == "linux" {
+		return defaultLinuxAppPaths[app]
+	}
+	return defaultAppPaths[app]
+}
+
+// config.ini の形式のバージョン。キーを追加・変更したら上げて migrateConfig で移行する
+const currentConfigVersion = 1
+
+// newDefaultConfig は初期値の設定を返す
+func newDefaultConfig() *ini.File {
+	cfg := ini.Empty()
+	for _, app := range appNames {
+		cfg.Section("").Key(app).SetValue(defaultAppPath(app))
+	}
+	cfg.Section("UI").Key("BannerPath").SetValue(defaultBannerPath)
+	cfg.Section("UI").Key("Accent").SetValue("")
+	cfg.Section("UI").Key("Base").SetValue("System")
+	cfg.Section("Launch").Key("ConfirmLaunch").SetValue("false")
+	cfg.Section("Launch").Key("PreviewCommand").SetValue("false")
+	cfg.Section("Launch").Key("MaxOutputLineLength").SetValue(strconv.Itoa(defaultMaxOutputLineLength))
+	for _, app := range appNames {
+		cfg.Section("LaunchArgs").Key(app).SetValue("")
+	}
+	cfg.Section("Last").Key("Project").SetValue("")
+	cfg.Section("Last").Key("App").SetValue(appNames[0])
+	cfg.Section("Last").Key("Version").SetValue("")
+	cfg.Section("Orbit").Key("ConfigVersion").SetValue(strconv.Itoa(currentConfigVersion))
+	return cfg
+}
+
+// loadConfig は config.ini を読み込み、古い形式であれば移行して保存する。
+// 移行前のファイルは config.v<旧バージョン>.bak.ini に残す。
+// ファイルが読み込めない場合は config.corrupt-<日時>.ini に退避して初期値で作り直し、
+// ユーザーに知らせる内容を notice で返す。退避できない場合はファイルに触れず、初期値をメモリ上でだけ使う
+func loadConfig() (cfg *ini.File, notice string) {
+	cfg, err := ini.Load(configFile)
+	if err == nil {
+		version := cfg.Section("Orbit").Key("ConfigVersion").MustInt(0)
+		if migrateConfig(cfg) {
+			if err := backupConfigFile(fmt.Sprintf("config.v%d.bak.ini", version)); err != nil {
+				log.Printf("Failed to back up %s, not saving the migrated config: %v", configFile, err)
+			} else if err := saveConfig(cfg, configFile); err != nil {
+				log.Printf("Failed to save migrated %s: %v", configFile, err)
+			}
+		}
+		return cfg, ""
+	}
+
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("%s not found, creating it with defaults", configFile)
+	} else {
+		log.Printf("Failed to load %s: %v", configFile, err)
+		notice = fmt.Sprintf("%s could not be read:\n%s\n\n", configFile, strings.TrimSpace(err.Error()))
+		// 日時を付けて、後から Reset to Defaults などで上書きされないようにする
+		backup := "config.corrupt-" + time.Now().Format("20060102-150405") + ".ini"
+		if err := backupConfigFile(backup); err != nil {
+			// 権限や他のプログラムによるロックで読めないだけの場合もあるため、唯一の設定を上書きしない
+			log.Printf("Failed to back up %s, leaving it untouched: %v", configFile, err)
+			return newDefaultConfig(), notice + "Default settings are used for now. The file was left untouched."
+		}
+		notice += "Your settings have been reset to defaults.\nThe previous file was backed up to " + backup + "."
+	}
+
+	if err := saveConfig(newDefaultConfig(), configFile); err != nil {
+		log.Printf("Failed to create %s: %v", configFile, err)
+		return newDefaultConfig(), notice
+	}
+	if cfg, err = ini.Load(configFile); err != nil {
+		return newDefaultConfig(), notice
+	}
+	return cfg, notice
+}
+
+// migrateConfig は古い形式の cfg に足りないキーを初期値で補い、ConfigVersion を更新する。
+// cfg を変更した場合は true を返す。新しい Orbit で書かれた設定は変更しない
+func migrateConfig(cfg *ini.File) bool {
+	version := cfg.Section("Orbit").Key("ConfigVersion").MustInt(0)
+	if version == currentConfigVersion {
+		return false
+	}
+	if version > currentConfigVersion {
+		log.Printf("%s is version %d, newer than this Orbit supports (%d); leaving it unchanged", configFile, version, currentConfigVersion)
+		return false
+	}
+	log.Printf("Migrating %s from version %d to %d", configFile, version, currentConfigVersion)
+	for _, section := range newDefaultConfig().Sections() {
+		for _, key := range section.Keys() {
+			if !cfg.Section(section.Name()).HasKey(key.Name()) {
+				cfg.Section(section.Name()).Key(key.Name()).SetValue(key.String())
+			}
+		}
+	}
+	cfg.Section("Orbit").Key("ConfigVersion").SetValue(strconv.Itoa(currentConfigVersion))
+	return true
+}
+
+// replaceConfig は dst の内容をすべて src の内容で置き換える。コメントも残す。
+// dst は画面の各所から参照されているため、ポインタを差し替えずに中身だけを入れ替える
+func replaceConfig(dst, src *ini.File) {
+	for _, section := range dst.Sections() {
+		if section.Name() != ini.DefaultSection {
+			dst.DeleteSection(section.Name())
+			continue
+		}
+		for _, key := range section.KeyStrings() {
+			section.DeleteKey(key)
+		}
+	}
+	for _, section := range src.Sections() {
+		dstSection := dst.Section(section.Name())
+		dstSection.Comment = section.Comment
+		for _, key := range section.Keys() {
+			dstKey := dstSection.Key(key.Name())
+			dstKey.SetValue(key.String())
+			dstKey.Comment = key.Comment
+		}
+	}
+}
+
+// backupConfigFile は現在の config.ini をそのまま path にコピーする
+func backupConfigFile(path string) error {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// resetConfig は cfg を初期値に戻して保存する。元の設定は configBackupFile に残す
+func resetConfig(cfg *ini.File) error {
+	if err := saveConfig(cfg, configBackupFile); err != nil {
+		return err
+	}
+	for _, section := range newDefaultConfig().Sections() {
+		for _, key := range section.Keys() {
+			cfg.Section(section.Name()).Key(key.Name()).SetValue(key.String())
+		}
+	}
+	return saveConfig(cfg, configFile)
+}
+
+// normalizeAppPath は設定されたアプリケーションのパスを絶対パスに揃える。
+// 前後の空白と引用符を取り除き、相対パスは Orbit の実行ファイルのあるフォルダを基準にする。
+// UNC パス（\\server\share\...）はそのまま絶対パスとして扱う。
+// blender のようにパス区切りを含まないコマンド名は PATH から探すため変更しない
+func normalizeAppPath(path string) string {
+	path = strings.Trim(strings.TrimSpace(path), `"`)
+	if path == "" || !strings.ContainsAny(path, `/\`) {
+		return path
+	}
+	path = filepath.Clean(filepath.FromSlash(path))
+	if !filepath.IsAbs(path) {
+		if exe, err := os.Executable(); err == nil {
+			path = filepath.Join(filepath.Dir(exe), path)
+		}
+	}
+	return path
+}
+
+// 共有用に値を伏せるキー名（小文字で部分一致）
+var secretKeyWords = []string{"token", "password", "secret"}
+
+// redactedConfigJSON は cfg を JSON にする。トークンなどの秘密情報は伏せ字にする
+func redactedConfigJSON(cfg *ini.File) (string, error) {
+	out := map[string]map[string]string{}
+	for _, section := range cfg.Sections() {
+		if len(section.Keys()) == 0 {
+			continue
+		}
+		values := map[string]string{}
+		for _, key := range section.Keys() {
+			values[key.Name()] = key.String()
+			for _, word := range secretKeyWords {
+				if strings.Contains(strings.ToLower(key.Name()), word) && key.String() != "" {
+					values[key.Name()] = "<redacted>"
+					break
+				}
+			}
+		}
+		out[section.Name()] = values
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// saveConfig は cfg を path に保存する。ini.File.SaveTo は書き込み途中で失敗するとファイルが壊れるため、
+// いったんメモリに書き出してから writeFileAtomic で置き換える
+func saveConfig(cfg *ini.File, path string) error {
+	var buf bytes.Buffer
+	if _, err := cfg.WriteTo(&buf); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// writeFileAtomic は一時ファイルに書き込んでから置き換えることで、途中で失敗しても path を壊さない
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"gopkg.in/ini.v1"
+)
+
+func mustLoadINI(t *testing.T, s string) *ini.File {
+	t.Helper()
+	cfg, err := ini.Load([]byte(s))
+	if err != nil {
+		t.Fatalf("ini.Load: %v", err)
+	}
+	return cfg
+}
+
+func TestMigrateConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          string
+		wantChanged bool
+		wantVersion int
+		wantFilled  bool // 足りないキーが初期値で補われるか
+	}{
+		{"version 0", "Maya = custom/maya\n", true, currentConfigVersion, true},
+		{"explicit version 0", "Maya = custom/maya\n[Orbit]\nConfigVersion = 0\n", true, currentConfigVersion, true},
+		{"current version", "Maya = custom/maya\n[Orbit]\nConfigVersion = " + strconv.Itoa(currentConfigVersion) + "\n", false, currentConfigVersion, false},
+		{"newer version", "Maya = custom/maya\n[Orbit]\nConfigVersion = " + strconv.Itoa(currentConfigVersion+1) + "\n", false, currentConfigVersion + 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := mustLoadINI(t, tt.in)
+			if got := migrateConfig(cfg); got != tt.wantChanged {
+				t.Errorf("migrateConfig() = %v, want %v", got, tt.wantChanged)
+			}
+			if got := cfg.Section("Orbit").Key("ConfigVersion").MustInt(-1); got != tt.wantVersion {
+				t.Errorf("ConfigVersion = %d, want %d", got, tt.wantVersion)
+			}
+			// 既存の値は上書きしない
+			if got := cfg.Section("").Key("Maya").String(); got != "custom/maya" {
+				t.Errorf("Maya = %q, want %q", got, "custom/maya")
+			}
+			if got := cfg.Section("Launch").HasKey("ConfirmLaunch"); got != tt.wantFilled {
+				t.Errorf("HasKey(ConfirmLaunch) = %v, want %v", got, tt.wantFilled)
+			}
+		})
+	}
+}
+
+func TestReplaceConfig(t *testing.T) {
+	dst := mustLoadINI(t, "Maya = old\nBlender = removed\n[Old]\nKey = removed\n[UI]\nBase = Dark\n")
+	src := mustLoadINI(t, "; paths\nMaya = new\n[UI]\n; theme\nBase = Light\n[New]\nKey = added\n")
+	replaceConfig(dst, src)
+
+	tests := []struct {
+		section, key string
+		want         string
+		wantExists   bool
+	}{
+		{"", "Maya", "new", true},
+		{"", "Blender", "", false},
+		{"Old", "Key", "", false},
+		{"UI", "Base", "Light", true},
+		{"New", "Key", "added", true},
+	}
+	for _, tt := range tests {
+		section, err := dst.GetSection(tt.section)
+		if err != nil || !section.HasKey(tt.key) {
+			if tt.wantExists {
+				t.Errorf("[%s] %s is missing", tt.section, tt.key)
+			}
+			continue
+		}
+		if !tt.wantExists {
+			t.Errorf("[%s] %s was not removed", tt.section, tt.key)
+			continue
+		}
+		if got := section.Key(tt.key).String(); got != tt.want {
+			t.Errorf("[%s] %s = %q, want %q", tt.section, tt.key, got, tt.want)
+		}
+	}
+	if _, err := dst.GetSection("Old"); err == nil {
+		t.Errorf("section [Old] was not removed")
+	}
+	// 直接編集で書いたコメントも残す
+	if got := dst.Section("").Key("Maya").Comment; got != "; paths" {
+		t.Errorf("Maya comment = %q, want %q", got, "; paths")
+	}
+	if got := dst.Section("UI").Key("Base").Comment; got != "; theme" {
+		t.Errorf("Base comment = %q, want %q", got, "; theme")
+	}
+}
+
+func TestNormalizeAppPath(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable: %v", err)
+	}
+	abs := filepath.Join(os.TempDir(), "bin", "blender")
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"blank", "  ", ""},
+		{"command name", "blender", "blender"},
+		{"command name with spaces and quotes", ` "blender" `, "blender"},
+		{"absolute", abs, abs},
+		{"quoted absolute", `"` + abs + `"`, abs},
+		{"relative", "tools/blender", filepath.Join(filepath.Dir(exe), "tools", "blender")},
+		{"unclean relative", "./tools/../bin/blender", filepath.Join(filepath.Dir(exe), "bin", "blender")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeAppPath(tt.in); got != tt.want {
+				t.Errorf("normalizeAppPath(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
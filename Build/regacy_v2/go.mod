@@ -4,6 +4,7 @@ go 1.22.2
 
 require (
 	fyne.io/fyne/v2 v2.4.5
+	golang.org/x/sys v0.13.0
 	gopkg.in/ini.v1 v1.67.0
 )
 
@@ -32,7 +33,6 @@ require (
 	golang.org/x/image v0.11.0 // indirect
 	golang.org/x/mobile v0.0.0-20230531173138-3c911d8e3eda // indirect
 	golang.org/x/net v0.17.0 // indirect
-	golang.org/x/sys v0.13.0 // indirect
 	golang.org/x/text v0.13.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20200227125254-8fa46927fb4f // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -0,0 +1,7 @@
+package main
+
+import "errors"
+
+// 別の Orbit が既に起動している場合に acquireInstanceLock が返すエラー。
+// 画面にそのまま表示するため、ロックファイルの場所などの詳細はラップして付け加える
+var errAlreadyRunning = errors.New("Orbit is already running")
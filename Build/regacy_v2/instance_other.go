@@ -0,0 +1,35 @@
+//go:build !windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+const lockFile = "orbit.lock"
+
+// acquireInstanceLock はロックファイルを flock で排他ロックして多重起動を防ぐ。終了時に release を呼ぶこと。
+// ロックはプロセスが終了するとカーネルが外すため、異常終了しても次の起動を妨げない
+func acquireInstanceLock() (release func(), err error) {
+	f, err := os.OpenFile(lockFile, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			path, absErr := filepath.Abs(lockFile)
+			if absErr != nil {
+				path = lockFile
+			}
+			return nil, fmt.Errorf("%w (%s is locked by another process)", errAlreadyRunning, path)
+		}
+		return nil, err
+	}
+	// ファイルは消さずに残す。消すと別の Orbit が開いたままの古いファイルとロックが食い違う
+	return func() { f.Close() }, nil
+}
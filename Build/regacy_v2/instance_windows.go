@@ -0,0 +1,22 @@
+//go:build windows
+
+package main
+
+import "golang.org/x/sys/windows"
+
+// acquireInstanceLock は名前付きミューテックスで多重起動を防ぐ。終了時に release を呼ぶこと
+func acquireInstanceLock() (release func(), err error) {
+	name, err := windows.UTF16PtrFromString(`Local\OrbitLauncher`)
+	if err != nil {
+		return nil, err
+	}
+	h, err := windows.CreateMutex(nil, false, name)
+	if err == windows.ERROR_ALREADY_EXISTS {
+		windows.CloseHandle(h)
+		return nil, errAlreadyRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	return func() { windows.CloseHandle(h) }, nil
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"gopkg.in/ini.v1"
+)
+
+// 表示する出力の1行の既定の最大文字数
+const defaultMaxOutputLineLength = 500
+
+// launchOptions は config.ini の [Launch] セクションの設定
+type launchOptions struct {
+	Confirm             bool // 起動前に設定の概要を確認する
+	Preview             bool // 実行前にコマンドを確認する
+	MaxOutputLineLength int  // 表示する出力の1行の最大文字数（0 以下は無制限）
+}
+
+// loadLaunchOptions は cfg から起動時の設定を読み込む
+func loadLaunchOptions(cfg *ini.File) launchOptions {
+	section := cfg.Section("Launch")
+	return launchOptions{
+		Confirm:             section.Key("ConfirmLaunch").MustBool(false),
+		Preview:             section.Key("PreviewCommand").MustBool(false),
+		MaxOutputLineLength: section.Key("MaxOutputLineLength").MustInt(defaultMaxOutputLineLength),
+	}
+}
+
+// launchTarget は起動するアプリケーションとメイン画面の入力値
+type launchTarget struct {
+	Project string
+	App     string // appNames のいずれか
+	Path    string // config.ini に設定された実行ファイルのパス
+	Version string
+	Args    string // 追加の起動引数（parseArgs で分割する）
+}
+
+// newLaunchCommand は appPath を起動するコマンドを組み立てる。args は --version の後に追加する。
+// シェルスクリプト（.sh）は bash で、Python スクリプト（.py）は python で実行する
+func newLaunchCommand(appPath, version string, args []string) *exec.Cmd {
+	args = append([]string{"--version", version}, args...)
+	switch strings.ToLower(filepath.Ext(appPath)) {
+	case ".sh":
+		return exec.Command("bash", append([]string{appPath}, args...)...)
+	case ".py":
+		python := "python3"
+		if runtime.GOOS == "windows" {
+			python = "python"
+		}
+		return exec.Command(python, append([]string{appPath}, args...)...)
+	}
+	return exec.Command(appPath, args...)
+}
+
+// parseArgs は起動引数の文字列を空白で分割する。
+// "..." または '...' で囲んだ部分は空白を含めて1つの引数として扱う
+func parseArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in launch arguments", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// ショートカットのリンク先を PowerShell で調べるときの制限時間
+const shortcutResolveTimeout = 5 * time.Second
+
+// resolveAppPath は起動する実行ファイルのパスを返す。ショートカット（.lnk）の場合はリンク先を返す
+func resolveAppPath(path string) (string, error) {
+	path = normalizeAppPath(path)
+	if path == "" {
+		return "", fmt.Errorf("application path is not set")
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".lnk") {
+		return path, nil
+	}
+	if runtime.GOOS != "windows" {
+		return "", fmt.Errorf("shortcut %s can only be resolved on Windows", path)
+	}
+	powershell, err := exec.LookPath("powershell")
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve shortcut %s: PowerShell is not available, set the target path directly", path)
+	}
+	// UI から呼ばれるため、PowerShell が応答しない場合でも画面が固まらないように時間を区切る
+	ctx, cancel := context.WithTimeout(context.Background(), shortcutResolveTimeout)
+	defer cancel()
+	script := "(New-Object -ComObject WScript.Shell).CreateShortcut('" + strings.ReplaceAll(path, "'", "''") + "').TargetPath"
+	out, err := exec.CommandContext(ctx, powershell, "-NoProfile", "-NonInteractive", "-Command", script).Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("timed out resolving shortcut %s after %s", path, shortcutResolveTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve shortcut %s: %w", path, err)
+	}
+	target := strings.TrimSpace(string(out))
+	if target == "" {
+		return "", fmt.Errorf("shortcut %s has no target", path)
+	}
+	return target, nil
+}
+
+// launchApplication は target を起動する。
+// opts.Confirm が true の場合は設定の概要を、opts.Preview が true の場合はコマンドを実行前に確認する
+func launchApplication(target launchTarget, opts launchOptions, window fyne.Window) {
+	path, err := resolveAppPath(target.Path)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	target.Path = path
+	args, err := parseArgs(target.Args)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	cmd := newLaunchCommand(target.Path, target.Version, args)
+	run := func() {
+		if opts.Preview {
+			showCommandPreview(cmd, opts, window)
+		} else {
+			runLaunchCommand(cmd, opts, window)
+		}
+	}
+	if !opts.Confirm {
+		run()
+		return
+	}
+
+	summary := widget.NewForm(
+		widget.NewFormItem("Project", widget.NewLabel(target.Project)),
+		widget.NewFormItem("Application", widget.NewLabel(target.App)),
+		widget.NewFormItem("Version", widget.NewLabel(target.Version)),
+		widget.NewFormItem("Path", widget.NewLabel(target.Path)),
+		widget.NewFormItem("Arguments", widget.NewLabel(target.Args)),
+		widget.NewFormItem("Preview", widget.NewLabel(strconv.FormatBool(opts.Preview))),
+	)
+	dialog.ShowCustomConfirm("Launch Summary", "Launch", "Cancel", summary, func(ok bool) {
+		if ok {
+			run()
+		}
+	}, window)
+}
+
+// showCommandPreview は cmd の内容を表示し、Run が押されたら実行する
+func showCommandPreview(cmd *exec.Cmd, opts launchOptions, window fyne.Window) {
+	label := widget.NewLabel(describeCommand(cmd))
+	label.Wrapping = fyne.TextWrapBreak
+	d := dialog.NewCustomConfirm("Launch Preview", "Run", "Cancel", label, func(ok bool) {
+		if ok {
+			runLaunchCommand(cmd, opts, window)
+		}
+	}, window)
+	d.Resize(fyne.NewSize(480, 240))
+	d.Show()
+}
+
+func runLaunchCommand(cmd *exec.Cmd, opts launchOptions, window fyne.Window) {
+	defer timeOperation("Launch " + cmd.Path)()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		dialog.ShowError(err, window)
+	} else {
+		dialog.ShowInformation("Launch Success", "Output: "+cleanOutput(string(output), opts.MaxOutputLineLength), window)
+	}
+}
+
+// cleanOutput はプロセスの出力を表示用に整える。
+// \r で上書きされる進捗表示は最後の状態だけを残し、maxLine を超える行は切り詰める
+func cleanOutput(output string, maxLine int) string {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if j := strings.LastIndex(strings.TrimRight(line, "\r"), "\r"); j >= 0 {
+			line = line[j+1:]
+		}
+		line = strings.TrimRight(line, "\r")
+		if runes := []rune(line); maxLine > 0 && len(runes) > maxLine {
+			line = string(runes[:maxLine]) + "..."
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeCommand は cmd のコマンドライン、作業ディレクトリ、環境変数の上書きを表示用の文字列にする
+func describeCommand(cmd *exec.Cmd) string {
+	args := make([]string, len(cmd.Args))
+	for i, arg := range cmd.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"") {
+			arg = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+		}
+		args[i] = arg
+	}
+
+	dir := cmd.Dir
+	if dir == "" {
+		dir, _ = os.Getwd()
+	}
+
+	env := "(none)"
+	if cmd.Env != nil {
+		env = strings.Join(cmd.Env, "\n")
+	}
+
+	return "Command:\n" + strings.Join(args, " ") +
+		"\n\nWorking directory:\n" + dir +
+		"\n\nEnvironment overrides:\n" + env
+}
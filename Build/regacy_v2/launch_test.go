@@ -0,0 +1,71 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"spaces only", "  \t ", nil},
+		{"plain", "-a b", []string{"-a", "b"}},
+		{"repeated spaces and tabs", " -a \t b  ", []string{"-a", "b"}},
+		{"double quoted", `-proj "D:\My Project"`, []string{"-proj", `D:\My Project`}},
+		{"single quoted", `-name 'a b'`, []string{"-name", "a b"}},
+		{"empty double quoted", `""`, []string{""}},
+		{"empty single quoted between args", `-x '' y`, []string{"-x", "", "y"}},
+		{"quote inside arg", `a"b c"d`, []string{"ab cd"}},
+		{"other quote kept", `"it's"`, []string{"it's"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseArgs(tt.in)
+			if err != nil {
+				t.Fatalf("parseArgs(%q) returned error: %v", tt.in, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseArgs(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgsUnterminatedQuote(t *testing.T) {
+	for _, in := range []string{`"abc`, `-a 'b c`, `x"`} {
+		if got, err := parseArgs(in); err == nil {
+			t.Errorf("parseArgs(%q) = %q, want error", in, got)
+		}
+	}
+}
+
+func TestCleanOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		maxLine int
+		want    string
+	}{
+		{"plain", "a\nb", 0, "a\nb"},
+		{"crlf", "a\r\nb\r\n", 0, "a\nb\n"},
+		{"progress keeps last state", "10%\r50%\r100%\ndone", 0, "100%\ndone"},
+		{"progress with crlf", "10%\r100%\r\ndone", 0, "100%\ndone"},
+		{"trailing cr", "progress\r", 0, "progress"},
+		{"truncated", "abcdef", 3, "abc..."},
+		{"not truncated at limit", "abc", 3, "abc"},
+		{"unlimited", "abcdef", 0, "abcdef"},
+		{"truncated by rune", "あいうえ", 2, "あい..."},
+		{"truncated after progress", "0123456789\rabcdef", 4, "abcd..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanOutput(tt.in, tt.maxLine); got != tt.want {
+				t.Errorf("cleanOutput(%q, %d) = %q, want %q", tt.in, tt.maxLine, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,13 @@
 package main
 
 import (
-	"os/exec"
+	"errors"
+	"fmt"
+	"image/color"
+	"log"
+	"os"
+	"slices"
+	"strconv"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -14,39 +20,144 @@ import (
 
 func main() {
 	myApp := app.New()
+	// アプリ全体のアイコン。設定ウィンドウやタスクバーにも使われる
+	if icon := loadAsset(defaultIconPath, "icon.png"); icon != nil {
+		myApp.SetIcon(icon)
+	}
 	myWindow := myApp.NewWindow("Orbit")
-	myWindow.Resize(fyne.NewSize(300, 320)) // ウィンドウのサイズを設定
+	myWindow.Resize(fyne.NewSize(300, 360)) // ウィンドウのサイズを設定
 
-	// 画像を読み込む
-	banner := canvas.NewImageFromFile("../Img/banner.png")
-	banner.FillMode = canvas.ImageFillOriginal // 画像のサイズを変更せずに表示
-
-	cfg, err := ini.Load("config.ini")
-	if err != nil {
-		dialog.ShowError(err, myWindow)
+	// 同じ config.ini を複数の Orbit が書き換えないように多重起動を防ぐ
+	release, err := acquireInstanceLock()
+	switch {
+	case errors.Is(err, errAlreadyRunning):
+		log.Printf("Failed to start: %v", err)
+		d := dialog.NewInformation("Orbit", err.Error()+".", myWindow)
+		d.SetOnClosed(myApp.Quit)
+		d.Show()
+		myWindow.ShowAndRun()
 		return
+	case err != nil:
+		// ロックを取れないだけで起動はできるので、ログに残してロック無しで続ける
+		log.Printf("Failed to acquire instance lock, continuing without it: %v", err)
+	default:
+		defer release()
 	}
 
+	cfg, notice := loadConfig()
+
+	applyTheme(myApp, cfg)
+
+	// 画像を読み込む
+	banner := loadBanner(cfg.Section("UI").Key("BannerPath").String())
+	banner.FillMode = canvas.ImageFillOriginal // 画像のサイズを変更せずに表示
+
 	projectInput := widget.NewEntry()
 	projectInput.SetPlaceHolder("Enter Project Name")
 
-	appSelect := widget.NewSelect([]string{"Maya", "Blender", "AfterEffects", "Photoshop"}, func(value string) {})
-	appSelect.SetSelected("Maya") // Default selection
+	// アプリケーションごとの追加の起動引数。選択中のアプリケーションの値を表示する
+	argsInput := widget.NewEntry()
+	argsInput.SetPlaceHolder("e.g. -proj \"D:\\My Project\"")
+
+	appSelect := widget.NewSelect(appNames, func(value string) {
+		argsInput.SetText(cfg.Section("LaunchArgs").Key(value).String())
+	})
 
 	versionInput := widget.NewEntry()
 	versionInput.SetPlaceHolder("Enter Version")
 
-	launchButton := widget.NewButton("Launch", func() {
-		project := projectInput.Text
+	// 入力欄を config.ini の [Last] / [LaunchArgs] の値に合わせる
+	loadInputs := func() {
+		app := appNames[0]
+		if last := cfg.Section("Last").Key("App").String(); slices.Contains(appNames, last) {
+			app = last
+		}
+		appSelect.SetSelected(app)
+		argsInput.SetText(cfg.Section("LaunchArgs").Key(app).String())
+		projectInput.SetText(cfg.Section("Last").Key("Project").String())
+		versionInput.SetText(cfg.Section("Last").Key("Version").String())
+	}
+	loadInputs()
+
+	launch := func() {
 		app := appSelect.Selected
-		version := versionInput.Text
-		appPath := cfg.Section("").Key(app).String()
-		launchApplication(project, appPath, version, myWindow)
-	})
+		// 次回の起動時に復元できるように入力内容を保存する（引数はアプリケーションごと）
+		cfg.Section("LaunchArgs").Key(app).SetValue(argsInput.Text)
+		cfg.Section("Last").Key("Project").SetValue(projectInput.Text)
+		cfg.Section("Last").Key("App").SetValue(app)
+		cfg.Section("Last").Key("Version").SetValue(versionInput.Text)
+		if err := saveConfig(cfg, configFile); err != nil {
+			log.Printf("Failed to save last used settings: %v", err)
+		}
+		target := launchTarget{
+			Project: projectInput.Text,
+			App:     app,
+			Path:    cfg.Section("").Key(app).String(),
+			Version: versionInput.Text,
+			Args:    argsInput.Text,
+		}
+		launchApplication(target, loadLaunchOptions(cfg), myWindow)
+	}
+	launchButton := widget.NewButton("Launch", launch)
+
+	// キーボードだけで操作できるように、入力欄で Enter を押しても起動する
+	projectInput.OnSubmitted = func(string) { launch() }
+	versionInput.OnSubmitted = func(string) { launch() }
+	argsInput.OnSubmitted = func(string) { launch() }
+
+	// 起動時に環境を確認し、問題があればウィンドウ上部に表示する
+	selfTestLabel := widget.NewLabel("")
+	selfTestLabel.Wrapping = fyne.TextWrapWord
+	updateSelfTest := func() string {
+		summary, critical := selfTestSummary(runSelfTest(cfg))
+		selfTestLabel.SetText(summary)
+		selfTestLabel.Importance = widget.WarningImportance
+		if critical {
+			selfTestLabel.Importance = widget.DangerImportance
+		}
+		if summary == "" {
+			selfTestLabel.Hide()
+		} else {
+			selfTestLabel.Show()
+		}
+		return summary
+	}
+	updateSelfTest()
+
+	// 設定ファイルを読み直した後に画面へ反映する
+	refreshUI := func() {
+		applyTheme(myApp, cfg)
+		banner.Resource = bannerResource(cfg.Section("UI").Key("BannerPath").String())
+		banner.Refresh()
+		updateSelfTest()
+	}
+
+	// 設定を初期値に戻した後や直接編集した後は入力欄も戻す。古い入力が次の起動で書き戻されないようにする
+	resetUI := func() {
+		loadInputs()
+		refreshUI()
+	}
 
 	menuBar := fyne.NewMainMenu(
 		fyne.NewMenu("File",
-			fyne.NewMenuItem("Edit Config", func() { showConfigEditor(myApp, cfg) }),
+			fyne.NewMenuItem("Edit Config", func() { showConfigEditor(myApp, cfg, refreshUI, resetUI) }),
+			fyne.NewMenuItem("Advanced: Edit Raw Config", func() { showRawConfigEditor(myApp, cfg, resetUI) }),
+			fyne.NewMenuItem("Copy Config to Clipboard", func() {
+				text, err := redactedConfigJSON(cfg)
+				if err != nil {
+					dialog.ShowError(err, myWindow)
+					return
+				}
+				myWindow.Clipboard().SetContent(text)
+				dialog.ShowInformation("Config Copied", "Configuration (with secrets redacted) has been copied to the clipboard.", myWindow)
+			}),
+			fyne.NewMenuItem("Run Self-Test", func() {
+				if summary := updateSelfTest(); summary != "" {
+					dialog.ShowInformation("Self-Test", summary, myWindow)
+				} else {
+					dialog.ShowInformation("Self-Test", "All checks passed.", myWindow)
+				}
+			}),
 		),
 	)
 	myWindow.SetMainMenu(menuBar)
@@ -54,63 +165,209 @@ func main() {
 	// ウィジェットコンテンツの作成
 	content := container.NewVBox(
 		banner, // ここで画像を追加
+		selfTestLabel,
 		widget.NewForm(
 			widget.NewFormItem("Set Project", projectInput),
 			widget.NewFormItem("Application", appSelect),
 			widget.NewFormItem("Use Version", versionInput),
+			widget.NewFormItem("Launch Args", argsInput),
 		),
 		launchButton,
 	)
 
 	myWindow.SetContent(content)
-	myWindow.ShowAndRun()
-}
-
-func launchApplication(project, appPath, version string, window fyne.Window) {
-	cmd := exec.Command(appPath, "--version", version)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		dialog.ShowError(err, window)
-	} else {
-		dialog.ShowInformation("Launch Success", "Output: "+string(output), window)
+	// 起動直後にプロジェクト名を入力できるようにフォーカスを合わせる（Tab の移動順は指定せず Fyne の既定に任せる）
+	myWindow.Canvas().Focus(projectInput)
+	if notice != "" {
+		dialog.ShowInformation("Config Reset", notice, myWindow)
 	}
+	myWindow.ShowAndRun()
 }
 
-func showConfigEditor(app fyne.App, cfg *ini.File) {
+// showConfigEditor は設定を編集するウィンドウを開く。
+// 保存に成功したときは onSaved を、初期値に戻したときは onReset を呼ぶ
+func showConfigEditor(app fyne.App, cfg *ini.File, onSaved, onReset func()) {
 	w := app.NewWindow("Edit Config") // 新しいウィンドウを作成
-	w.Resize(fyne.NewSize(665, 275))  // ウィンドウのサイズを設定
+	w.Resize(fyne.NewSize(665, 475))  // ウィンドウのサイズを設定
 
 	form := &widget.Form{}
 	// 各アプリケーション名と対応するパスをテキストボックスに事前に表示
-	for _, app := range []string{"Maya", "Blender", "AfterEffects", "Photoshop"} {
+	for _, app := range appNames {
 		entry := widget.NewEntry()
 		entry.SetText(cfg.Section("").Key(app).String()) // config.ini からパスを読み込み、テキストボックスに設定
 		form.Append(app, entry)
 	}
+	bannerEntry := widget.NewEntry()
+	bannerEntry.SetText(cfg.Section("UI").Key("BannerPath").String())
+	bannerEntry.SetPlaceHolder(defaultBannerPath)
+	form.Append("Banner", bannerEntry)
+	accentEntry := widget.NewEntry()
+	accentEntry.SetPlaceHolder("#RRGGBB (empty for default)")
+	accentEntry.SetText(cfg.Section("UI").Key("Accent").String())
+	var presetNames []string
+	for _, p := range accentPresets {
+		presetNames = append(presetNames, p.Name)
+	}
+	presetSelect := widget.NewSelect(presetNames, func(name string) {
+		for _, p := range accentPresets {
+			if p.Name == name {
+				accentEntry.SetText(p.Hex)
+			}
+		}
+	})
+	presetSelect.PlaceHolder = "Preset"
+	pickButton := widget.NewButton("Pick...", func() {
+		picker := dialog.NewColorPicker("Accent Color", "Choose an accent color", func(c color.Color) {
+			accentEntry.SetText(formatHexColor(c))
+		}, w)
+		picker.Advanced = true
+		if c, err := parseHexColor(accentEntry.Text); err == nil {
+			picker.SetColor(c)
+		}
+		picker.Show()
+	})
+	form.Append("Accent", container.NewBorder(nil, nil, presetSelect, pickButton, accentEntry))
+	baseSelect := widget.NewSelect(baseVariants, nil)
+	baseSelect.SetSelected(cfg.Section("UI").Key("Base").MustString("System"))
+	form.Append("Base", baseSelect)
+	confirmCheck := widget.NewCheck("Show a summary before launching", nil)
+	confirmCheck.SetChecked(cfg.Section("Launch").Key("ConfirmLaunch").MustBool(false))
+	form.Append("Confirm", confirmCheck)
+	previewCheck := widget.NewCheck("Show the command before launching", nil)
+	previewCheck.SetChecked(cfg.Section("Launch").Key("PreviewCommand").MustBool(false))
+	form.Append("Preview", previewCheck)
 
 	saveButton := widget.NewButton("Save", func() {
+		if accentEntry.Text != "" {
+			if _, err := parseHexColor(accentEntry.Text); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+		}
 		// フォームの各エントリから新しい値を取得して設定ファイルを更新
-		for i, app := range []string{"Maya", "Blender", "AfterEffects", "Photoshop"} {
-			cfg.Section("").Key(app).SetValue(form.Items[i].Widget.(*widget.Entry).Text)
+		for i, app := range appNames {
+			entry := form.Items[i].Widget.(*widget.Entry)
+			entry.SetText(normalizeAppPath(entry.Text))
+			cfg.Section("").Key(app).SetValue(entry.Text)
 		}
+		cfg.Section("UI").Key("BannerPath").SetValue(bannerEntry.Text)
+		cfg.Section("UI").Key("Accent").SetValue(accentEntry.Text)
+		cfg.Section("UI").Key("Base").SetValue(baseSelect.Selected)
+		cfg.Section("Launch").Key("ConfirmLaunch").SetValue(strconv.FormatBool(confirmCheck.Checked))
+		cfg.Section("Launch").Key("PreviewCommand").SetValue(strconv.FormatBool(previewCheck.Checked))
 		// 設定をファイルに保存
-		if err := cfg.SaveTo("config.ini"); err != nil {
+		if err := saveConfig(cfg, configFile); err != nil {
 			dialog.ShowError(err, w)
 		} else {
+			onSaved()
 			dialog.ShowInformation("Config Saved", "Configuration has been saved successfully.", w)
 			w.Close()
 		}
 	})
 
+	resetButton := widget.NewButton("Reset to Defaults", func() {
+		dialog.ShowConfirm("Reset to Defaults", "Restore all settings to their defaults?\nThe current config will be backed up to "+configBackupFile+".", func(ok bool) {
+			if !ok {
+				return
+			}
+			if err := resetConfig(cfg); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			// フォームの表示を初期値に更新
+			for i, app := range appNames {
+				form.Items[i].Widget.(*widget.Entry).SetText(cfg.Section("").Key(app).String())
+			}
+			bannerEntry.SetText(cfg.Section("UI").Key("BannerPath").String())
+			accentEntry.SetText(cfg.Section("UI").Key("Accent").String())
+			baseSelect.SetSelected(cfg.Section("UI").Key("Base").String())
+			confirmCheck.SetChecked(cfg.Section("Launch").Key("ConfirmLaunch").MustBool(false))
+			previewCheck.SetChecked(cfg.Section("Launch").Key("PreviewCommand").MustBool(false))
+			onReset()
+			dialog.ShowInformation("Config Reset", "Configuration has been reset to defaults.", w)
+		}, w)
+	})
+
 	cancelButton := widget.NewButton("Cancel", func() {
 		w.Close()
 	})
 
 	content := container.NewVBox(
 		form,
-		container.NewHBox(saveButton, cancelButton),
+		container.NewHBox(saveButton, resetButton, cancelButton),
 	)
 
 	w.SetContent(content)
+	w.Canvas().Focus(form.Items[0].Widget.(*widget.Entry))
 	w.Show()
 }
+
+// showRawConfigEditor は config.ini をテキストのまま編集するウィンドウを開く。
+// 保存に成功すると cfg を読み直して onSaved を呼ぶ
+func showRawConfigEditor(app fyne.App, cfg *ini.File, onSaved func()) {
+	w := app.NewWindow("Edit Raw Config")
+	w.Resize(fyne.NewSize(665, 435))
+
+	data, readErr := os.ReadFile(configFile)
+	editor := widget.NewMultiLineEntry()
+	editor.TextStyle = fyne.TextStyle{Monospace: true}
+	editor.SetText(string(data))
+
+	// ini として読み込めるか確認する
+	validate := func() error {
+		if _, err := ini.Load([]byte(editor.Text)); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+		return nil
+	}
+
+	validateButton := widget.NewButton("Validate", func() {
+		if err := validate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		dialog.ShowInformation("Validate", "Config is valid.", w)
+	})
+
+	saveButton := widget.NewButton("Save", func() {
+		if err := validate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if err := writeFileAtomic(configFile, []byte(editor.Text)); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		// Reload は読み込んだ内容を既存の値に重ねるだけで、削除したキーが残るため読み直して置き換える
+		edited, err := ini.Load(configFile)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		replaceConfig(cfg, edited)
+		if migrateConfig(cfg) {
+			if err := saveConfig(cfg, configFile); err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+		}
+		onSaved()
+		w.Close()
+	})
+
+	cancelButton := widget.NewButton("Cancel", func() {
+		w.Close()
+	})
+
+	content := container.NewBorder(nil, container.NewHBox(validateButton, saveButton, cancelButton), nil, nil, editor)
+	w.SetContent(content)
+	w.Show()
+
+	// 読み込めなかった場合に空の内容で config.ini を上書きしないよう、編集と保存をできなくする
+	if readErr != nil {
+		editor.Disable()
+		validateButton.Disable()
+		saveButton.Disable()
+		dialog.ShowError(readErr, w)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// checkResult は自己診断の1項目の結果
+type checkResult struct {
+	Name     string
+	Err      error
+	Critical bool // 失敗すると Orbit 自体が正しく動かない項目
+}
+
+// runSelfTest は起動に必要な環境を確認し、結果をログに出力する
+func runSelfTest(cfg *ini.File) []checkResult {
+	defer timeOperation("Self-test")()
+	results := []checkResult{
+		{Name: configFile + " writable", Err: checkWritable(configFile), Critical: true},
+	}
+	for _, app := range appNames {
+		path := normalizeAppPath(cfg.Section("").Key(app).String())
+		// パスが空のアプリケーションは使わないものとして確認しない（Linux 版の無いアプリケーションなど）
+		if path == "" {
+			continue
+		}
+		results = append(results, checkResult{Name: app, Err: checkExecutable(path)})
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			log.Printf("Self-test FAIL: %s: %v", r.Name, r.Err)
+		} else {
+			log.Printf("Self-test PASS: %s", r.Name)
+		}
+	}
+	return results
+}
+
+// checkWritable は path に書き込めるか確認する
+func checkWritable(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// checkExecutable は path に実行ファイルが存在するか確認する
+func checkExecutable(path string) error {
+	if path == "" {
+		return fmt.Errorf("path is not set")
+	}
+	// パス区切りを含まないコマンド名は起動時と同じく PATH から探す
+	if !strings.ContainsAny(path, `/\`) {
+		_, err := exec.LookPath(path)
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	return nil
+}
+
+// selfTestSummary は失敗した項目をまとめた文字列を返す。すべて成功した場合は空文字
+// critical は重要な項目が1つでも失敗しているかどうか
+func selfTestSummary(results []checkResult) (summary string, critical bool) {
+	var failed []string
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r.Name)
+			critical = critical || r.Critical
+		}
+	}
+	if len(failed) == 0 {
+		return "", false
+	}
+	return "Check failed: " + strings.Join(failed, ", ") + "\nFix the paths via File > Edit Config.", critical
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"gopkg.in/ini.v1"
+)
+
+// アクセントカラーのプリセット（名前と #RRGGBB）
+var accentPresets = []struct {
+	Name string
+	Hex  string
+}{
+	{"Blue", "#3D7BFF"},
+	{"Green", "#2EA043"},
+	{"Orange", "#F0883E"},
+	{"Purple", "#A371F7"},
+	{"Red", "#E5534B"},
+}
+
+// ベースの明るさの選択肢（空はOSの設定に従う）
+var baseVariants = []string{"System", "Dark", "Light"}
+
+// accentTheme は既定のテーマにアクセントカラーとベースの明るさを上書きする
+type accentTheme struct {
+	fyne.Theme
+	accent  color.Color // nil の場合は既定の色
+	variant string      // "Dark" / "Light" / それ以外はOSの設定
+}
+
+func (t *accentTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	switch t.variant {
+	case "Dark":
+		variant = theme.VariantDark
+	case "Light":
+		variant = theme.VariantLight
+	}
+	if t.accent != nil && (name == theme.ColorNamePrimary || name == theme.ColorNameButton) {
+		return t.accent
+	}
+	return t.Theme.Color(name, variant)
+}
+
+// applyTheme は config.ini の [UI] Accent / Base を読み込んでテーマを設定する
+func applyTheme(a fyne.App, cfg *ini.File) {
+	t := &accentTheme{Theme: theme.DefaultTheme(), variant: cfg.Section("UI").Key("Base").String()}
+	if hex := cfg.Section("UI").Key("Accent").String(); hex != "" {
+		if c, err := parseHexColor(hex); err == nil {
+			t.accent = c
+		}
+	}
+	a.Settings().SetTheme(t)
+}
+
+// parseHexColor は #RRGGBB 形式の文字列を色に変換する
+func parseHexColor(s string) (color.Color, error) {
+	if len(s) != 7 || !strings.HasPrefix(s, "#") {
+		return nil, fmt.Errorf("invalid color %q: expected #RRGGBB", s)
+	}
+	// Sscanf の %02x は途中の16進数以外の文字で読み込みをやめてもエラーにならないため、6桁すべてを確認する
+	rgb, err := hex.DecodeString(s[1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid color %q: expected #RRGGBB", s)
+	}
+	return color.NRGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff}, nil
+}
+
+// formatHexColor は色を #RRGGBB 形式の文字列にする
+func formatHexColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02X%02X%02X", n.R, n.G, n.B)
+}
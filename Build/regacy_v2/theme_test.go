@@ -0,0 +1,54 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.Color
+		wantErr bool
+	}{
+		{"#FF8000", color.NRGBA{R: 0xff, G: 0x80, B: 0x00, A: 0xff}, false},
+		{"#3d7bff", color.NRGBA{R: 0x3d, G: 0x7b, B: 0xff, A: 0xff}, false},
+		{"", nil, true},
+		{"FF8000", nil, true},
+		{"#FFF", nil, true},
+		{"#FF80000", nil, true},
+		{"#GG0000", nil, true},
+		{"#12345G", nil, true},
+		{"#1 2345", nil, true},
+		{"#+F0000", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseHexColor(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseHexColor(%q) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseHexColor(%q) returned error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseHexColor(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatHexColorRoundTrip(t *testing.T) {
+	for _, p := range accentPresets {
+		c, err := parseHexColor(p.Hex)
+		if err != nil {
+			t.Fatalf("parseHexColor(%q) returned error: %v", p.Hex, err)
+		}
+		if got := formatHexColor(c); got != p.Hex {
+			t.Errorf("formatHexColor(parseHexColor(%q)) = %q", p.Hex, got)
+		}
+	}
+}
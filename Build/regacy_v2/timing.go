@@ -0,0 +1,17 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// timeOperation は name の開始をログに出し、返した関数を呼ぶと経過時間をログに出す
+//
+//	defer timeOperation("Launch")()
+func timeOperation(name string) func() {
+	start := time.Now()
+	log.Printf("%s started", name)
+	return func() {
+		log.Printf("%s completed in %s", name, time.Since(start).Round(time.Millisecond))
+	}
+}